@@ -2,9 +2,10 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"encoding/json"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -17,6 +18,11 @@ type Payload struct {
 	Body string
 }
 
+const (
+	payloadContentType = "application/json"
+	taskType           = "log-body"
+)
+
 func handleStop(cancel context.CancelFunc) {
 	logger := logrus.New()
 	sigs := make(chan os.Signal, 1)
@@ -37,15 +43,16 @@ func runConsumer(ctx context.Context, taskQueue *taskqueue.TaskQueue) {
 		"operation": "consumer",
 	})
 
-	logger.Info("consuming task")
-	taskQueue.Consume(
-		ctx,
-		func(ctx context.Context, taskID uuid.UUID, payload interface{}) error {
-			logger.Printf("consumed task %s: %v\n", taskID, payload)
+	mux := taskqueue.NewServeMux()
+	mux.Use(taskqueue.Recoverer, taskqueue.Logger)
+	mux.HandleFunc(taskType, func(ctx context.Context, taskID uuid.UUID, payload []byte) error {
+		logger.Printf("consumed task %s: %s\n", taskID, payload)
 
-			return nil
-		},
-	)
+		return nil
+	})
+
+	logger.Info("consuming task")
+	taskQueue.Consume(ctx, mux.Consume)
 }
 
 func runProducer(ctx context.Context, taskQueue *taskqueue.TaskQueue) {
@@ -63,7 +70,14 @@ func runProducer(ctx context.Context, taskQueue *taskqueue.TaskQueue) {
 		case <-ticker.C:
 			logger.Info("producing task")
 
-			taskID, err := taskQueue.ProduceAt(ctx, &Payload{Body: fmt.Sprintf("%d", id)}, time.Now())
+			payload, err := json.Marshal(&Payload{Body: strconv.Itoa(id)})
+			if err != nil {
+				logger.WithError(err).Error("failed to marshal payload")
+
+				break
+			}
+
+			taskID, err := taskQueue.ProduceAt(ctx, taskType, payload, payloadContentType, time.Now())
 			if err != nil {
 				logger.WithError(err).Error("failed to enqueue task")
 
@@ -0,0 +1,215 @@
+package taskqueue
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Codec encodes and decodes a Task for storage in Redis, decoupling the wire representation
+// from the struct so that callers can plug in a format other than JSON.
+type Codec interface {
+	Encode(*Task) ([]byte, error)
+	Decode([]byte) (*Task, error)
+}
+
+// JSONCodec encodes tasks as JSON. It is the default Codec.
+type JSONCodec struct{}
+
+func (JSONCodec) Encode(task *Task) ([]byte, error) {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal task as json: %w", err)
+	}
+
+	return data, nil
+}
+
+func (JSONCodec) Decode(data []byte) (*Task, error) {
+	task := new(Task)
+
+	if err := json.Unmarshal(data, task); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task from json: %w", err)
+	}
+
+	return task, nil
+}
+
+// ProtoCodec encodes tasks as a hand-written, protobuf-wire-compatible byte stream (using
+// google.golang.org/protobuf/encoding/protowire directly rather than protoc-generated types),
+// avoiding the lossy JSON round-trip of arbitrary payloads (e.g. raw bytes, high-precision
+// timestamps). The field layout below is the wire contract non-Go producers/consumers must match:
+// field 1 id (bytes), 2 payload (bytes), 3 content_type (string), 4 retry_count (varint),
+// 5 wait_nanos (varint), 6 produced_at_unix_nano (varint), 7 retention_nanos (varint),
+// 8 unique_key (string), 9 type (string).
+type ProtoCodec struct{}
+
+const (
+	protoFieldID          = protowire.Number(1)
+	protoFieldPayload     = protowire.Number(2)
+	protoFieldContentType = protowire.Number(3)
+	protoFieldRetryCount  = protowire.Number(4)
+	protoFieldWaitNanos   = protowire.Number(5)
+	protoFieldProducedAt  = protowire.Number(6)
+	protoFieldRetention   = protowire.Number(7)
+	protoFieldUniqueKey   = protowire.Number(8)
+	protoFieldType        = protowire.Number(9)
+)
+
+func (ProtoCodec) Encode(task *Task) ([]byte, error) {
+	var buf []byte
+
+	buf = protowire.AppendTag(buf, protoFieldID, protowire.BytesType)
+	buf = protowire.AppendBytes(buf, task.ID[:])
+
+	buf = protowire.AppendTag(buf, protoFieldPayload, protowire.BytesType)
+	buf = protowire.AppendBytes(buf, task.Payload)
+
+	buf = protowire.AppendTag(buf, protoFieldContentType, protowire.BytesType)
+	buf = protowire.AppendString(buf, task.ContentType)
+
+	buf = protowire.AppendTag(buf, protoFieldRetryCount, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, uint64(task.RetryCount))
+
+	buf = protowire.AppendTag(buf, protoFieldWaitNanos, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, uint64(task.Wait))
+
+	buf = protowire.AppendTag(buf, protoFieldProducedAt, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, uint64(task.ProducedAt.UnixNano()))
+
+	buf = protowire.AppendTag(buf, protoFieldRetention, protowire.VarintType)
+	buf = protowire.AppendVarint(buf, uint64(task.Retention))
+
+	buf = protowire.AppendTag(buf, protoFieldUniqueKey, protowire.BytesType)
+	buf = protowire.AppendString(buf, task.UniqueKey)
+
+	buf = protowire.AppendTag(buf, protoFieldType, protowire.BytesType)
+	buf = protowire.AppendString(buf, task.Type)
+
+	return buf, nil
+}
+
+func (ProtoCodec) Decode(data []byte) (*Task, error) { //nolint:cyclop
+	task := new(Task)
+
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, fmt.Errorf("failed to consume tag: %w", protowire.ParseError(n))
+		}
+
+		data = data[n:]
+
+		switch num {
+		case protoFieldID:
+			id, m, err := consumeProtoBytes(data, typ)
+			if err != nil {
+				return nil, err
+			}
+
+			copy(task.ID[:], id)
+			data = data[m:]
+		case protoFieldPayload:
+			payload, m, err := consumeProtoBytes(data, typ)
+			if err != nil {
+				return nil, err
+			}
+
+			task.Payload = payload
+			data = data[m:]
+		case protoFieldContentType:
+			contentType, m, err := consumeProtoBytes(data, typ)
+			if err != nil {
+				return nil, err
+			}
+
+			task.ContentType = string(contentType)
+			data = data[m:]
+		case protoFieldRetryCount:
+			v, m, err := consumeProtoVarint(data, typ)
+			if err != nil {
+				return nil, err
+			}
+
+			task.RetryCount = int(v)
+			data = data[m:]
+		case protoFieldWaitNanos:
+			v, m, err := consumeProtoVarint(data, typ)
+			if err != nil {
+				return nil, err
+			}
+
+			task.Wait = time.Duration(v)
+			data = data[m:]
+		case protoFieldProducedAt:
+			v, m, err := consumeProtoVarint(data, typ)
+			if err != nil {
+				return nil, err
+			}
+
+			task.ProducedAt = time.Unix(0, int64(v)).UTC()
+			data = data[m:]
+		case protoFieldRetention:
+			v, m, err := consumeProtoVarint(data, typ)
+			if err != nil {
+				return nil, err
+			}
+
+			task.Retention = time.Duration(v)
+			data = data[m:]
+		case protoFieldUniqueKey:
+			uniqueKey, m, err := consumeProtoBytes(data, typ)
+			if err != nil {
+				return nil, err
+			}
+
+			task.UniqueKey = string(uniqueKey)
+			data = data[m:]
+		case protoFieldType:
+			taskType, m, err := consumeProtoBytes(data, typ)
+			if err != nil {
+				return nil, err
+			}
+
+			task.Type = string(taskType)
+			data = data[m:]
+		default:
+			m := protowire.ConsumeFieldValue(num, typ, data)
+			if m < 0 {
+				return nil, fmt.Errorf("failed to skip unknown field %d: %w", num, protowire.ParseError(m))
+			}
+
+			data = data[m:]
+		}
+	}
+
+	return task, nil
+}
+
+func consumeProtoBytes(data []byte, typ protowire.Type) ([]byte, int, error) {
+	if typ != protowire.BytesType {
+		return nil, 0, fmt.Errorf("%w: expected bytes wire type, got %d", ErrInvalidTaskType, typ)
+	}
+
+	v, n := protowire.ConsumeBytes(data)
+	if n < 0 {
+		return nil, 0, fmt.Errorf("failed to consume bytes: %w", protowire.ParseError(n))
+	}
+
+	return v, n, nil
+}
+
+func consumeProtoVarint(data []byte, typ protowire.Type) (uint64, int, error) {
+	if typ != protowire.VarintType {
+		return 0, 0, fmt.Errorf("%w: expected varint wire type, got %d", ErrInvalidTaskType, typ)
+	}
+
+	v, n := protowire.ConsumeVarint(data)
+	if n < 0 {
+		return 0, 0, fmt.Errorf("failed to consume varint: %w", protowire.ParseError(n))
+	}
+
+	return v, n, nil
+}
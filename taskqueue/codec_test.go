@@ -0,0 +1,125 @@
+package taskqueue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func testTask() *Task {
+	return &Task{
+		ID:          uuid.New(),
+		Payload:     []byte("hello world"),
+		ContentType: "application/json",
+		RetryCount:  3,
+		Wait:        5 * time.Second,
+		ProducedAt:  time.Unix(1700000000, 0).UTC(),
+		Retention:   time.Hour,
+		UniqueKey:   "taskqueue:ns:unique:q:deadbeef",
+		Type:        "send_email",
+	}
+}
+
+func assertTaskEqual(t *testing.T, want, got *Task) {
+	t.Helper()
+
+	if got.ID != want.ID {
+		t.Errorf("ID = %s, want %s", got.ID, want.ID)
+	}
+
+	if string(got.Payload) != string(want.Payload) {
+		t.Errorf("Payload = %q, want %q", got.Payload, want.Payload)
+	}
+
+	if got.ContentType != want.ContentType {
+		t.Errorf("ContentType = %q, want %q", got.ContentType, want.ContentType)
+	}
+
+	if got.RetryCount != want.RetryCount {
+		t.Errorf("RetryCount = %d, want %d", got.RetryCount, want.RetryCount)
+	}
+
+	if got.Wait != want.Wait {
+		t.Errorf("Wait = %s, want %s", got.Wait, want.Wait)
+	}
+
+	if !got.ProducedAt.Equal(want.ProducedAt) {
+		t.Errorf("ProducedAt = %s, want %s", got.ProducedAt, want.ProducedAt)
+	}
+
+	if got.Retention != want.Retention {
+		t.Errorf("Retention = %s, want %s", got.Retention, want.Retention)
+	}
+
+	if got.UniqueKey != want.UniqueKey {
+		t.Errorf("UniqueKey = %q, want %q", got.UniqueKey, want.UniqueKey)
+	}
+
+	if got.Type != want.Type {
+		t.Errorf("Type = %q, want %q", got.Type, want.Type)
+	}
+}
+
+func TestJSONCodecRoundTrip(t *testing.T) {
+	want := testTask()
+
+	codec := JSONCodec{}
+
+	data, err := codec.Encode(want)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	got, err := codec.Decode(data)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	assertTaskEqual(t, want, got)
+}
+
+func TestProtoCodecRoundTrip(t *testing.T) {
+	want := testTask()
+
+	codec := ProtoCodec{}
+
+	data, err := codec.Encode(want)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	got, err := codec.Decode(data)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	assertTaskEqual(t, want, got)
+}
+
+func TestProtoCodecRoundTrip_EmptyUniqueKey(t *testing.T) {
+	want := testTask()
+	want.UniqueKey = ""
+
+	codec := ProtoCodec{}
+
+	data, err := codec.Encode(want)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	got, err := codec.Decode(data)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	assertTaskEqual(t, want, got)
+}
+
+func TestProtoCodecDecode_InvalidData(t *testing.T) {
+	codec := ProtoCodec{}
+
+	if _, err := codec.Decode([]byte{0xff, 0xff, 0xff}); err == nil {
+		t.Fatal("Decode() error = nil, want non-nil")
+	}
+}
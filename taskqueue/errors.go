@@ -0,0 +1,39 @@
+package taskqueue
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// StatusOK is the first element of the {"OK", delay} reply consume.lua returns when there is no
+// task ready to be consumed.
+const StatusOK = "OK"
+
+var (
+	ErrNoTaskToConsume  = errors.New("no task to consume")
+	ErrInvalidTaskType  = errors.New("invalid task type")
+	ErrMaxTaskReties    = errors.New("task reached max retries")
+	ErrDuplicateTask    = errors.New("duplicate task")
+	ErrNoHandlerForType = errors.New("no handler registered for task type")
+)
+
+// TaskLostError indicates that a task could neither be processed nor requeued for retry,
+// and was therefore permanently lost.
+type TaskLostError struct {
+	TaskID uuid.UUID
+	err    error
+}
+
+func (e *TaskLostError) Error() string {
+	return fmt.Sprintf("task %s lost: %s", e.TaskID, e.err)
+}
+
+func (e *TaskLostError) Unwrap() error {
+	return e.err
+}
+
+func ErrTaskLost(taskID uuid.UUID, err error) error {
+	return &TaskLostError{TaskID: taskID, err: err}
+}
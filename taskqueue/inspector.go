@@ -0,0 +1,211 @@
+package taskqueue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Inspector reads and manages the state of queues in a namespace, for building operational
+// tooling on top of TaskQueue without resorting to ad-hoc redis-cli surgery.
+type Inspector struct {
+	redis               Redis
+	namespace           string
+	codec               Codec
+	deadLetterRetention time.Duration
+}
+
+// QueueInfo summarizes the state of a single queue.
+type QueueInfo struct {
+	QueueKey   string
+	Pending    int64
+	Scheduled  int64
+	InProgress int64
+	Dead       int64
+}
+
+// NewInspector builds an Inspector for the given namespace. codec must decode tasks the same way
+// the TaskQueue that produced them encodes them; pass nil to use the default JSONCodec.
+// deadLetterRetention should match the TaskQueue's Options.DeadLetterRetention, since it controls
+// how long ArchiveTask's dead-letter entries survive before the janitor purges them; pass 0 to use
+// the same default TaskQueue does.
+func NewInspector(redisClient Redis, namespace string, codec Codec, deadLetterRetention time.Duration) *Inspector {
+	if codec == nil {
+		codec = JSONCodec{}
+	}
+
+	if deadLetterRetention == 0 {
+		deadLetterRetention = defaultDeadLetterRetention
+	}
+
+	return &Inspector{redis: redisClient, namespace: namespace, codec: codec, deadLetterRetention: deadLetterRetention}
+}
+
+// Queues lists the queue keys that currently have tasks tracked under the namespace.
+func (i *Inspector) Queues() ([]string, error) {
+	keys, err := i.redis.Keys(context.Background(), queueKeyPattern(i.namespace)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queues: %w", err)
+	}
+
+	queues := make([]string, 0, len(keys))
+	for _, key := range keys {
+		queues = append(queues, queueKeyFromTaskQueueKey(i.namespace, key))
+	}
+
+	return queues, nil
+}
+
+// QueueInfo returns counts of pending, scheduled, in-progress and dead tasks for a queue.
+func (i *Inspector) QueueInfo(ctx context.Context, queueKey string) (*QueueInfo, error) {
+	now := fmt.Sprintf("%d", time.Now().Unix())
+
+	pending, err := i.redis.ZCount(ctx, taskQueueKey(i.namespace, queueKey), "-inf", now).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count pending tasks: %w", err)
+	}
+
+	scheduled, err := i.redis.ZCount(ctx, taskQueueKey(i.namespace, queueKey), "("+now, "+inf").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count scheduled tasks: %w", err)
+	}
+
+	inProgress, err := i.redis.ZCard(ctx, inProgressQueueKey(i.namespace, queueKey)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count in-progress tasks: %w", err)
+	}
+
+	dead, err := i.redis.ZCount(ctx, deadTaskQueueKey(i.namespace, queueKey), "-inf", "+inf").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count dead tasks: %w", err)
+	}
+
+	return &QueueInfo{
+		QueueKey:   queueKey,
+		Pending:    pending,
+		Scheduled:  scheduled,
+		InProgress: inProgress,
+		Dead:       dead,
+	}, nil
+}
+
+// ListPending pages through tasks that are ready to run now.
+func (i *Inspector) ListPending(ctx context.Context, queueKey string, page, size int64) ([]*Task, error) {
+	now := fmt.Sprintf("%d", time.Now().Unix())
+
+	return i.listByScore(ctx, taskQueueKey(i.namespace, queueKey), "-inf", now, page, size)
+}
+
+// ListScheduled pages through tasks that are waiting for their execute-at time.
+func (i *Inspector) ListScheduled(ctx context.Context, queueKey string, page, size int64) ([]*Task, error) {
+	now := fmt.Sprintf("%d", time.Now().Unix())
+
+	return i.listByScore(ctx, taskQueueKey(i.namespace, queueKey), "("+now, "+inf", page, size)
+}
+
+// ListDead pages through tasks that exhausted their retries.
+func (i *Inspector) ListDead(ctx context.Context, queueKey string, page, size int64) ([]*Task, error) {
+	return i.listByScore(ctx, deadTaskQueueKey(i.namespace, queueKey), "-inf", "+inf", page, size)
+}
+
+// ListInProgress lists the tasks currently claimed by a worker for this queue.
+func (i *Inspector) ListInProgress(ctx context.Context, queueKey string) ([]*Task, error) {
+	return i.listByScore(ctx, inProgressQueueKey(i.namespace, queueKey), "-inf", "+inf", 0, -1)
+}
+
+func (i *Inspector) listByScore(ctx context.Context, zsetKey, min, max string, page, size int64) ([]*Task, error) {
+	ids, err := i.redis.ZRangeByScore(ctx, zsetKey, &redis.ZRangeBy{
+		Min:    min,
+		Max:    max,
+		Offset: page * size,
+		Count:  size,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task ids: %w", err)
+	}
+
+	tasks := make([]*Task, 0, len(ids))
+
+	for _, id := range ids {
+		task, err := i.getTaskByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+func (i *Inspector) getTaskByID(ctx context.Context, id string) (*Task, error) {
+	data, err := i.redis.HGet(ctx, taskHashKeyPrefix(i.namespace)+id, "data").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task %s: %w", id, err)
+	}
+
+	task, err := i.codec.Decode([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode task %s: %w", id, err)
+	}
+
+	return task, nil
+}
+
+// DeleteTask removes a task from the queue entirely, wherever it currently sits: pending or
+// scheduled, in-progress, retained as completed, or dead.
+func (i *Inspector) DeleteTask(ctx context.Context, queueKey, id string) error {
+	if err := i.redis.ZRem(ctx, taskQueueKey(i.namespace, queueKey), id).Err(); err != nil {
+		return fmt.Errorf("failed to remove task from queue: %w", err)
+	}
+
+	if err := i.redis.ZRem(ctx, inProgressQueueKey(i.namespace, queueKey), id).Err(); err != nil {
+		return fmt.Errorf("failed to remove task from in-progress set: %w", err)
+	}
+
+	if err := i.redis.ZRem(ctx, completedTaskQueueKey(i.namespace, queueKey), id).Err(); err != nil {
+		return fmt.Errorf("failed to remove task from completed set: %w", err)
+	}
+
+	if err := i.redis.ZRem(ctx, deadTaskQueueKey(i.namespace, queueKey), id).Err(); err != nil {
+		return fmt.Errorf("failed to remove task from dead queue: %w", err)
+	}
+
+	if err := i.redis.HDel(ctx, taskHashKeyPrefix(i.namespace)+id, "data", "unique_key").Err(); err != nil {
+		return fmt.Errorf("failed to delete task data: %w", err)
+	}
+
+	return nil
+}
+
+// RunTask moves a scheduled task so that it becomes immediately runnable.
+func (i *Inspector) RunTask(ctx context.Context, queueKey, id string) error {
+	if err := i.redis.ZAdd(ctx, taskQueueKey(i.namespace, queueKey), &redis.Z{
+		Score:  float64(time.Now().Unix()),
+		Member: id,
+	}).Err(); err != nil {
+		return fmt.Errorf("failed to run task: %w", err)
+	}
+
+	return nil
+}
+
+// ArchiveTask moves a task straight to the dead-letter queue without running it, where it survives
+// for deadLetterRetention before the janitor purges it, the same as a task dead-lettered for
+// exhausting its retries.
+func (i *Inspector) ArchiveTask(ctx context.Context, queueKey, id string) error {
+	if err := i.redis.ZRem(ctx, taskQueueKey(i.namespace, queueKey), id).Err(); err != nil {
+		return fmt.Errorf("failed to remove task from queue: %w", err)
+	}
+
+	if err := i.redis.ZAdd(ctx, deadTaskQueueKey(i.namespace, queueKey), &redis.Z{
+		Score:  float64(time.Now().Add(i.deadLetterRetention).Unix()),
+		Member: id,
+	}).Err(); err != nil {
+		return fmt.Errorf("failed to archive task: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package taskqueue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/mock/gomock"
+)
+
+func stringSliceCmd(ctx context.Context, values ...string) *redis.StringSliceCmd {
+	cmd := redis.NewStringSliceCmd(ctx)
+	cmd.SetVal(values)
+
+	return cmd
+}
+
+func intValCmd(ctx context.Context, val int64) *redis.IntCmd {
+	cmd := redis.NewIntCmd(ctx)
+	cmd.SetVal(val)
+
+	return cmd
+}
+
+func TestInspector_QueueInfo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRedis := NewMockRedis(ctrl)
+	inspector := NewInspector(mockRedis, "ns", nil, 0)
+
+	ctx := context.Background()
+
+	mockRedis.EXPECT().
+		ZCount(ctx, taskQueueKey("ns", "q"), "-inf", gomock.Any()).
+		Return(intValCmd(ctx, 2))
+	mockRedis.EXPECT().
+		ZCount(ctx, taskQueueKey("ns", "q"), gomock.Any(), "+inf").
+		Return(intValCmd(ctx, 3))
+	mockRedis.EXPECT().
+		ZCard(ctx, inProgressQueueKey("ns", "q")).
+		Return(intValCmd(ctx, 1))
+	mockRedis.EXPECT().
+		ZCount(ctx, deadTaskQueueKey("ns", "q"), "-inf", "+inf").
+		Return(intValCmd(ctx, 4))
+
+	info, err := inspector.QueueInfo(ctx, "q")
+	if err != nil {
+		t.Fatalf("QueueInfo() error = %v", err)
+	}
+
+	want := &QueueInfo{QueueKey: "q", Pending: 2, Scheduled: 3, InProgress: 1, Dead: 4}
+	if *info != *want {
+		t.Errorf("QueueInfo() = %+v, want %+v", info, want)
+	}
+}
+
+func TestInspector_Queues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRedis := NewMockRedis(ctrl)
+	inspector := NewInspector(mockRedis, "ns", nil, 0)
+
+	ctx := context.Background()
+
+	mockRedis.EXPECT().
+		Keys(gomock.Any(), queueKeyPattern("ns")).
+		Return(stringSliceCmd(ctx, taskQueueKey("ns", "q1"), taskQueueKey("ns", "q2")))
+
+	queues, err := inspector.Queues()
+	if err != nil {
+		t.Fatalf("Queues() error = %v", err)
+	}
+
+	want := []string{"q1", "q2"}
+	if len(queues) != len(want) || queues[0] != want[0] || queues[1] != want[1] {
+		t.Errorf("Queues() = %v, want %v", queues, want)
+	}
+}
+
+func TestInspector_DeleteTask_RemovesFromEveryKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRedis := NewMockRedis(ctrl)
+	inspector := NewInspector(mockRedis, "ns", nil, 0)
+
+	ctx := context.Background()
+	const id = "11111111-1111-1111-1111-111111111111"
+
+	mockRedis.EXPECT().ZRem(ctx, taskQueueKey("ns", "q"), id).Return(intCmd(ctx))
+	mockRedis.EXPECT().ZRem(ctx, inProgressQueueKey("ns", "q"), id).Return(intCmd(ctx))
+	mockRedis.EXPECT().ZRem(ctx, completedTaskQueueKey("ns", "q"), id).Return(intCmd(ctx))
+	mockRedis.EXPECT().ZRem(ctx, deadTaskQueueKey("ns", "q"), id).Return(intCmd(ctx))
+	mockRedis.EXPECT().HDel(ctx, taskHashKeyPrefix("ns")+id, "data", "unique_key").Return(intCmd(ctx))
+
+	if err := inspector.DeleteTask(ctx, "q", id); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+}
+
+func TestInspector_RunTask_AddsToQueueWithCurrentScore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRedis := NewMockRedis(ctrl)
+	inspector := NewInspector(mockRedis, "ns", nil, 0)
+
+	ctx := context.Background()
+	const id = "11111111-1111-1111-1111-111111111111"
+
+	mockRedis.EXPECT().
+		ZAdd(ctx, taskQueueKey("ns", "q"), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, members ...*redis.Z) *redis.IntCmd {
+			if len(members) != 1 || members[0].Member != id {
+				t.Errorf("ZAdd members = %+v, want member %s", members, id)
+			}
+
+			if members[0].Score > float64(time.Now().Unix()) {
+				t.Errorf("ZAdd score = %v, want <= now", members[0].Score)
+			}
+
+			return intCmd(ctx)
+		})
+
+	if err := inspector.RunTask(ctx, "q", id); err != nil {
+		t.Fatalf("RunTask() error = %v", err)
+	}
+}
+
+// TestInspector_ArchiveTask_ScoresByRetentionNotNow covers the bug where the dead-letter ZSET
+// score was set to time.Now() instead of an expiry timestamp: janitor.lua treats that score as an
+// absolute expiry and purges anything <= now, so an archived task scored at "now" would be purged
+// the very next janitor tick instead of surviving for deadLetterRetention like a task dead-lettered
+// for exhausting its retries.
+func TestInspector_ArchiveTask_ScoresByRetentionNotNow(t *testing.T) {
+	const retention = time.Hour
+
+	ctrl := gomock.NewController(t)
+	mockRedis := NewMockRedis(ctrl)
+	inspector := NewInspector(mockRedis, "ns", nil, retention)
+
+	ctx := context.Background()
+	const id = "11111111-1111-1111-1111-111111111111"
+
+	mockRedis.EXPECT().ZRem(ctx, taskQueueKey("ns", "q"), id).Return(intCmd(ctx))
+	mockRedis.EXPECT().
+		ZAdd(ctx, deadTaskQueueKey("ns", "q"), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, members ...*redis.Z) *redis.IntCmd {
+			if len(members) != 1 || members[0].Member != id {
+				t.Errorf("ZAdd members = %+v, want member %s", members, id)
+			}
+
+			wantScore := float64(time.Now().Add(retention).Unix())
+			if members[0].Score < wantScore-1 || members[0].Score > wantScore+1 {
+				t.Errorf("ZAdd score = %v, want ~%v (now + retention)", members[0].Score, wantScore)
+			}
+
+			return intCmd(ctx)
+		})
+
+	if err := inspector.ArchiveTask(ctx, "q", id); err != nil {
+		t.Fatalf("ArchiveTask() error = %v", err)
+	}
+}
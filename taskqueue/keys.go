@@ -0,0 +1,52 @@
+package taskqueue
+
+import (
+	"crypto/sha256"
+	"fmt"
+)
+
+// Key layout shared by TaskQueue and Inspector. Tasks are addressable by ID: their data lives in
+// a hash keyed by ID, while the various ZSETs below only ever store IDs, so that the queue can be
+// inspected and individual tasks can be managed without reaching into full payloads.
+
+func taskQueueKey(namespace, queueKey string) string {
+	return fmt.Sprintf("taskqueue:%s:tasks:%s", namespace, queueKey)
+}
+
+func taskHashKeyPrefix(namespace string) string {
+	return fmt.Sprintf("taskqueue:%s:t:", namespace)
+}
+
+func taskHashKey(namespace string, id fmt.Stringer) string {
+	return taskHashKeyPrefix(namespace) + id.String()
+}
+
+func inProgressQueueKey(namespace, queueKey string) string {
+	return fmt.Sprintf("taskqueue:%s:inprogress:%s", namespace, queueKey)
+}
+
+func deadTaskQueueKey(namespace, queueKey string) string {
+	return fmt.Sprintf("taskqueue:%s:dead:%s", namespace, queueKey)
+}
+
+func completedTaskQueueKey(namespace, queueKey string) string {
+	return fmt.Sprintf("taskqueue:%s:completed:%s", namespace, queueKey)
+}
+
+func uniqueTaskKey(namespace, queueKey string, payload []byte) string {
+	return fmt.Sprintf("taskqueue:%s:unique:%s:%x", namespace, queueKey, sha256.Sum256(payload))
+}
+
+func notifyChannelKey(namespace, queueKey string) string {
+	return fmt.Sprintf("taskqueue:%s:notify:%s", namespace, queueKey)
+}
+
+func queueKeyPattern(namespace string) string {
+	return fmt.Sprintf("taskqueue:%s:tasks:*", namespace)
+}
+
+func queueKeyFromTaskQueueKey(namespace, key string) string {
+	prefix := fmt.Sprintf("taskqueue:%s:tasks:", namespace)
+
+	return key[len(prefix):]
+}
@@ -0,0 +1,15 @@
+package taskqueue
+
+import "github.com/sirupsen/logrus"
+
+func newLogger() *logrus.Entry {
+	return logrus.New().WithField("component", "taskqueue")
+}
+
+func withTaskLabels(logger *logrus.Entry, task *Task) *logrus.Entry {
+	return logger.WithFields(logrus.Fields{
+		"task_id":     task.ID,
+		"retry_count": task.RetryCount,
+		"wait":        task.Wait,
+	})
+}
@@ -0,0 +1,237 @@
+package taskqueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// newLuaTestQueue spins up a miniredis instance and a TaskQueue backed by a real go-redis client
+// pointed at it, so consume.lua, janitor.lua and recovery.lua are actually loaded and executed
+// instead of stubbed out by MockRedis. This is the regression test for the consume.lua typo that
+// made ScriptLoad fail on every real Redis while passing against the mock.
+func newLuaTestQueue(t *testing.T) (*TaskQueue, *miniredis.Miniredis) {
+	t.Helper()
+
+	return newLuaTestQueueWithOptions(t, &Options{ //nolint:exhaustruct
+		Namespace:     "ns",
+		QueueKey:      "q",
+		EnableJanitor: true,
+	})
+}
+
+func newLuaTestQueueWithOptions(t *testing.T, options *Options) (*TaskQueue, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()}) //nolint:exhaustruct
+
+	options.StorageAddress = server.Addr()
+
+	queue, err := NewTaskQueue(context.Background(), client, options)
+	if err != nil {
+		t.Fatalf("NewTaskQueue() error = %v", err)
+	}
+
+	return queue, server
+}
+
+func TestLua_ConsumeRunsReadyTask(t *testing.T) {
+	queue, _ := newLuaTestQueue(t)
+	ctx := context.Background()
+
+	id, err := queue.ProduceAt(ctx, "send_email", []byte("hello"), "text/plain", time.Now())
+	if err != nil {
+		t.Fatalf("ProduceAt() error = %v", err)
+	}
+
+	var gotID uuid.UUID
+
+	var gotType string
+
+	wait, err := queue.consume(ctx, func(_ context.Context, taskID uuid.UUID, payload []byte, taskType, _ string) error {
+		gotID = taskID
+		gotType = taskType
+
+		if string(payload) != "hello" {
+			t.Errorf("payload = %q, want %q", payload, "hello")
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("consume() error = %v", err)
+	}
+
+	if wait != 0 {
+		t.Errorf("wait = %s, want 0", wait)
+	}
+
+	if gotID != id {
+		t.Errorf("consumed task id = %s, want %s", gotID, id)
+	}
+
+	if gotType != "send_email" {
+		t.Errorf("consumed task type = %q, want %q", gotType, "send_email")
+	}
+}
+
+func TestLua_ConsumeReportsWaitWhenQueueEmpty(t *testing.T) {
+	queue, _ := newLuaTestQueue(t)
+	ctx := context.Background()
+
+	executeAt := time.Now().Add(10 * time.Second)
+
+	if _, err := queue.ProduceAt(ctx, "send_email", []byte("hello"), "text/plain", executeAt); err != nil {
+		t.Fatalf("ProduceAt() error = %v", err)
+	}
+
+	wait, err := queue.consume(ctx, func(context.Context, uuid.UUID, []byte, string, string) error {
+		t.Fatal("consume func should not run before executeAt")
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("consume() error = %v", err)
+	}
+
+	if wait <= 0 || wait > 10*time.Second {
+		t.Errorf("wait = %s, want between 0 and 10s", wait)
+	}
+}
+
+// TestLua_ConsumeCleansUpUniqueKeyFieldOnCompletion covers the leak where a task produced via
+// ProduceAtUnique left its "unique_key" hash field behind after a clean, non-retained completion:
+// consume.lua already DELs the dedup lock key itself when popping the task, but the per-task hash
+// row (taskqueue:{ns}:t:{id}) never became empty and so was never reclaimed by Redis.
+func TestLua_ConsumeCleansUpUniqueKeyFieldOnCompletion(t *testing.T) {
+	queue, server := newLuaTestQueue(t)
+	ctx := context.Background()
+
+	id, err := queue.ProduceAtUnique(ctx, "send_email", []byte("hello"), "text/plain", time.Now(), time.Minute)
+	if err != nil {
+		t.Fatalf("ProduceAtUnique() error = %v", err)
+	}
+
+	if _, err := queue.consume(ctx, func(context.Context, uuid.UUID, []byte, string, string) error {
+		return nil
+	}); err != nil {
+		t.Fatalf("consume() error = %v", err)
+	}
+
+	fields, err := server.HKeys(taskHashKey(queue.namespace, id))
+	if err != nil && !errors.Is(err, miniredis.ErrKeyNotFound) {
+		t.Fatalf("HKeys() error = %v", err)
+	}
+
+	if len(fields) != 0 {
+		t.Errorf("task hash fields = %v, want none left behind after completion", fields)
+	}
+}
+
+func TestLua_JanitorPurgesExpiredRetainedTasks(t *testing.T) {
+	// cleanupExpired compares the retention ZSET's scores against a real wall-clock timestamp, so
+	// the retention window must be short enough to elapse during the test rather than simulated
+	// via miniredis.FastForward (which only advances miniredis's own key-TTL clock).
+	const retention = 20 * time.Millisecond
+
+	queue, _ := newLuaTestQueue(t)
+	ctx := context.Background()
+
+	id, err := queue.ProduceAtWithOptions(ctx, "send_email", []byte("hello"), "text/plain", time.Now(), retention)
+	if err != nil {
+		t.Fatalf("ProduceAtWithOptions() error = %v", err)
+	}
+
+	if _, err := queue.consume(ctx, func(context.Context, uuid.UUID, []byte, string, string) error {
+		return nil
+	}); err != nil {
+		t.Fatalf("consume() error = %v", err)
+	}
+
+	hashKey := taskHashKey(queue.namespace, id)
+	if _, err := queue.redis.HGet(ctx, hashKey, "data").Result(); err != nil {
+		t.Fatalf("expected retained task data to still exist before expiry: %v", err)
+	}
+
+	time.Sleep(2 * retention)
+	queue.cleanupExpired(ctx, queue.completedTaskQueueKey, newLogger())
+
+	if _, err := queue.redis.HGet(ctx, hashKey, "data").Result(); !errors.Is(err, redis.Nil) {
+		t.Errorf("expected retained task data to be purged after expiry, HGet error = %v", err)
+	}
+}
+
+func TestLua_RecoveryRequeuesStuckInProgressTask(t *testing.T) {
+	// recovery.lua compares the in-progress ZSET's visibility-deadline scores against a real
+	// wall-clock timestamp, so the timeout must be short enough to elapse during the test rather
+	// than simulated via miniredis.FastForward (which only advances miniredis's own key-TTL clock).
+	const visibilityTimeout = 20 * time.Millisecond
+
+	queue, _ := newLuaTestQueueWithOptions(t, &Options{ //nolint:exhaustruct
+		Namespace:         "ns",
+		QueueKey:          "q",
+		VisibilityTimeout: visibilityTimeout,
+	})
+	ctx := context.Background()
+
+	id, err := queue.ProduceAt(ctx, "send_email", []byte("hello"), "text/plain", time.Now())
+	if err != nil {
+		t.Fatalf("ProduceAt() error = %v", err)
+	}
+
+	task, wait, err := queue.getTask(ctx)
+	if err != nil {
+		t.Fatalf("getTask() error = %v", err)
+	}
+
+	if wait != 0 || task.ID != id {
+		t.Fatalf("getTask() = %+v, %s, want task %s with no wait", task, wait, id)
+	}
+
+	// Simulate a worker crashing before completeInProgressTask runs: the claimed task stays
+	// parked in the in-progress ZSET until its visibility deadline passes.
+	time.Sleep(2 * visibilityTimeout)
+
+	queue.recoverStuckTasks(ctx, newLogger())
+
+	// recoverTask routes the stuck task through produceRetry, which schedules it a second in the
+	// future rather than immediately, so getTask still won't return it yet: assert on the
+	// re-queued task's stored data directly instead of waiting out the backoff.
+	ids, err := queue.redis.ZRangeByScore(ctx, queue.taskQueueKey, &redis.ZRangeBy{Min: "-inf", Max: "+inf"}).Result()
+	if err != nil {
+		t.Fatalf("ZRangeByScore() error = %v", err)
+	}
+
+	if len(ids) != 1 || ids[0] != id.String() {
+		t.Fatalf("taskQueueKey members = %v, want [%s]", ids, id)
+	}
+
+	data, err := queue.redis.HGet(ctx, taskHashKey(queue.namespace, id), "data").Result()
+	if err != nil {
+		t.Fatalf("HGet() error = %v", err)
+	}
+
+	recovered, err := queue.codec.Decode([]byte(data))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if recovered.RetryCount != 1 {
+		t.Errorf("recovered task RetryCount = %d, want 1", recovered.RetryCount)
+	}
+
+	inProgress, err := queue.redis.ZCard(ctx, queue.inProgressQueueKey).Result()
+	if err != nil {
+		t.Fatalf("ZCard() error = %v", err)
+	}
+
+	if inProgress != 0 {
+		t.Errorf("inProgressQueueKey has %d members, want 0", inProgress)
+	}
+}
@@ -0,0 +1,53 @@
+package taskqueue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Recoverer recovers from panics raised by the wrapped handler and turns them into a regular
+// error, so that a single bad task retries like any other failure instead of crashing the
+// consumer goroutine.
+func Recoverer(next Handler) Handler {
+	return HandlerFunc(func(ctx context.Context, id uuid.UUID, payload []byte) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("task %s panicked: %v", id, r)
+			}
+		}()
+
+		return next.ServeTask(ctx, id, payload)
+	})
+}
+
+// Timeout wraps the context passed to the handler with a deadline, so that a handler stuck
+// longer than timeout fails and retries instead of holding the task in progress indefinitely.
+func Timeout(timeout time.Duration) MiddlewareFunc {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, id uuid.UUID, payload []byte) error {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			return next.ServeTask(ctx, id, payload)
+		})
+	}
+}
+
+// Logger logs the outcome of every task the wrapped handler processes.
+func Logger(next Handler) Handler {
+	return HandlerFunc(func(ctx context.Context, id uuid.UUID, payload []byte) error {
+		logger := newLogger().WithField("task_id", id)
+
+		err := next.ServeTask(ctx, id, payload)
+		if err != nil {
+			logger.WithError(err).Error("task failed")
+		} else {
+			logger.Debug("task succeeded")
+		}
+
+		return err
+	})
+}
@@ -0,0 +1,71 @@
+package taskqueue
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRecoverer_TurnsPanicIntoError(t *testing.T) {
+	handler := Recoverer(HandlerFunc(func(context.Context, uuid.UUID, []byte) error {
+		panic("boom")
+	}))
+
+	err := handler.ServeTask(context.Background(), uuid.New(), nil)
+	if err == nil {
+		t.Fatal("ServeTask() error = nil, want non-nil")
+	}
+
+	if !strings.Contains(err.Error(), "boom") {
+		t.Errorf("ServeTask() error = %v, want it to mention the panic value", err)
+	}
+}
+
+func TestRecoverer_PassesThroughNormalReturn(t *testing.T) {
+	wantErr := errors.New("failed")
+
+	handler := Recoverer(HandlerFunc(func(context.Context, uuid.UUID, []byte) error {
+		return wantErr
+	}))
+
+	if err := handler.ServeTask(context.Background(), uuid.New(), nil); !errors.Is(err, wantErr) {
+		t.Errorf("ServeTask() error = %v, want %v", err, wantErr)
+	}
+}
+
+func TestTimeout_CancelsContextAfterDuration(t *testing.T) {
+	handler := Timeout(10 * time.Millisecond)(HandlerFunc(func(ctx context.Context, _ uuid.UUID, _ []byte) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	}))
+
+	err := handler.ServeTask(context.Background(), uuid.New(), nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("ServeTask() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestLogger_PassesThroughResult(t *testing.T) {
+	wantErr := errors.New("failed")
+
+	handler := Logger(HandlerFunc(func(context.Context, uuid.UUID, []byte) error {
+		return wantErr
+	}))
+
+	if err := handler.ServeTask(context.Background(), uuid.New(), nil); !errors.Is(err, wantErr) {
+		t.Errorf("ServeTask() error = %v, want %v", err, wantErr)
+	}
+
+	handler = Logger(HandlerFunc(func(context.Context, uuid.UUID, []byte) error {
+		return nil
+	}))
+
+	if err := handler.ServeTask(context.Background(), uuid.New(), nil); err != nil {
+		t.Errorf("ServeTask() error = %v, want nil", err)
+	}
+}
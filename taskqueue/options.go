@@ -0,0 +1,106 @@
+package taskqueue
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultMaxRetries          = 25
+	defaultOperationTimeout    = 30 * time.Second
+	defaultDeadLetterRetention = 7 * 24 * time.Hour
+	defaultJanitorInterval     = time.Minute
+	defaultMaxPollInterval     = 30 * time.Second
+	defaultVisibilityTimeout   = 30 * time.Second
+	defaultRecoveryInterval    = 30 * time.Second
+)
+
+// Options configures a TaskQueue.
+type Options struct {
+	// Namespace isolates the keys of this queue from other queues sharing the same Redis instance.
+	Namespace string
+
+	// QueueKey identifies the queue within the namespace.
+	QueueKey string
+
+	// WorkerID identifies this consumer among others consuming from the same queue.
+	WorkerID string
+
+	// StorageAddress is the address of the Redis instance backing the queue.
+	StorageAddress string
+
+	// MaxRetries is the maximum number of times a task is retried before being given up on.
+	// A negative value means tasks are retried indefinitely.
+	MaxRetries int
+
+	// OperationTimeout is stored on the TaskQueue; no Redis operation or consume call is bounded
+	// by it.
+	OperationTimeout time.Duration
+
+	// Codec controls how tasks are encoded for storage in Redis. Defaults to JSONCodec.
+	Codec Codec
+
+	// DeadLetterRetention is how long an exhausted task is kept in the dead-letter queue before
+	// the janitor purges it. Defaults to 7 days.
+	DeadLetterRetention time.Duration
+
+	// EnableJanitor starts a background goroutine that purges expired dead-letter and completed
+	// tasks. Disabled by default.
+	EnableJanitor bool
+
+	// JanitorInterval is how often the janitor goroutine runs. Defaults to 1 minute.
+	JanitorInterval time.Duration
+
+	// MaxPollInterval bounds how long Consume sleeps between checks of the queue when it has no
+	// scheduled tasks and receives no wakeup notification. Defaults to 30 seconds.
+	MaxPollInterval time.Duration
+
+	// VisibilityTimeout is how long a task may stay claimed by a worker before the recovery
+	// goroutine considers it stuck (e.g. the worker crashed) and puts it back on the queue for
+	// another worker to pick up. Consumers processing a task longer than this must call
+	// TaskQueue.Extend to push the deadline back. Defaults to 30 seconds.
+	VisibilityTimeout time.Duration
+
+	// RecoveryInterval is how often the recovery goroutine checks for stuck in-progress tasks.
+	// Defaults to 30 seconds.
+	RecoveryInterval time.Duration
+}
+
+func (o *Options) setDefaults() {
+	if o.WorkerID == "" {
+		o.WorkerID = uuid.New().String()
+	}
+
+	if o.MaxRetries == 0 {
+		o.MaxRetries = defaultMaxRetries
+	}
+
+	if o.OperationTimeout == 0 {
+		o.OperationTimeout = defaultOperationTimeout
+	}
+
+	if o.Codec == nil {
+		o.Codec = JSONCodec{}
+	}
+
+	if o.DeadLetterRetention == 0 {
+		o.DeadLetterRetention = defaultDeadLetterRetention
+	}
+
+	if o.JanitorInterval == 0 {
+		o.JanitorInterval = defaultJanitorInterval
+	}
+
+	if o.MaxPollInterval == 0 {
+		o.MaxPollInterval = defaultMaxPollInterval
+	}
+
+	if o.VisibilityTimeout == 0 {
+		o.VisibilityTimeout = defaultVisibilityTimeout
+	}
+
+	if o.RecoveryInterval == 0 {
+		o.RecoveryInterval = defaultRecoveryInterval
+	}
+}
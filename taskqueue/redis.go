@@ -0,0 +1,30 @@
+package taskqueue
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+//go:generate mockgen -source=redis.go -destination=redis_mock.go -package=taskqueue
+
+// Redis is the subset of the go-redis client that TaskQueue and Inspector depend on, so that it
+// can be mocked in tests.
+type Redis interface {
+	Get(ctx context.Context, key string) *redis.StringCmd
+	EvalSha(ctx context.Context, sha1 string, keys []string, args ...interface{}) *redis.Cmd
+	ScriptLoad(ctx context.Context, script string) *redis.StringCmd
+	ZAdd(ctx context.Context, key string, members ...*redis.Z) *redis.IntCmd
+	ZRem(ctx context.Context, key string, members ...interface{}) *redis.IntCmd
+	ZCard(ctx context.Context, key string) *redis.IntCmd
+	ZCount(ctx context.Context, key, min, max string) *redis.IntCmd
+	ZRangeByScore(ctx context.Context, key string, opt *redis.ZRangeBy) *redis.StringSliceCmd
+	HSet(ctx context.Context, key string, values ...interface{}) *redis.IntCmd
+	HGet(ctx context.Context, key, field string) *redis.StringCmd
+	HDel(ctx context.Context, key string, fields ...string) *redis.IntCmd
+	Keys(ctx context.Context, pattern string) *redis.StringSliceCmd
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd
+	Publish(ctx context.Context, channel string, message interface{}) *redis.IntCmd
+	Subscribe(ctx context.Context, channels ...string) *redis.PubSub
+}
@@ -7,8 +7,9 @@ package taskqueue
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
-	v8 "github.com/go-redis/redis/v8"
+	redis "github.com/go-redis/redis/v8"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -35,49 +36,124 @@ func (m *MockRedis) EXPECT() *MockRedisMockRecorder {
 	return m.recorder
 }
 
-// Del mocks base method.
-func (m *MockRedis) Del(ctx context.Context, keys ...string) *v8.IntCmd {
+// EvalSha mocks base method.
+func (m *MockRedis) EvalSha(ctx context.Context, sha1 string, keys []string, args ...interface{}) *redis.Cmd {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{ctx}
-	for _, a := range keys {
+	varargs := []interface{}{ctx, sha1, keys}
+	for _, a := range args {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "Del", varargs...)
-	ret0, _ := ret[0].(*v8.IntCmd)
+	ret := m.ctrl.Call(m, "EvalSha", varargs...)
+	ret0, _ := ret[0].(*redis.Cmd)
 	return ret0
 }
 
-// Del indicates an expected call of Del.
-func (mr *MockRedisMockRecorder) Del(ctx interface{}, keys ...interface{}) *gomock.Call {
+// EvalSha indicates an expected call of EvalSha.
+func (mr *MockRedisMockRecorder) EvalSha(ctx, sha1, keys interface{}, args ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{ctx}, keys...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockRedis)(nil).Del), varargs...)
+	varargs := append([]interface{}{ctx, sha1, keys}, args...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvalSha", reflect.TypeOf((*MockRedis)(nil).EvalSha), varargs...)
 }
 
-// EvalSha mocks base method.
-func (m *MockRedis) EvalSha(ctx context.Context, sha1 string, keys []string, args ...interface{}) *v8.Cmd {
+// Get mocks base method.
+func (m *MockRedis) Get(ctx context.Context, key string) *redis.StringCmd {
 	m.ctrl.T.Helper()
-	varargs := []interface{}{ctx, sha1, keys}
-	for _, a := range args {
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(*redis.StringCmd)
+	return ret0
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRedisMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRedis)(nil).Get), ctx, key)
+}
+
+// HDel mocks base method.
+func (m *MockRedis) HDel(ctx context.Context, key string, fields ...string) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range fields {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "EvalSha", varargs...)
-	ret0, _ := ret[0].(*v8.Cmd)
+	ret := m.ctrl.Call(m, "HDel", varargs...)
+	ret0, _ := ret[0].(*redis.IntCmd)
 	return ret0
 }
 
-// EvalSha indicates an expected call of EvalSha.
-func (mr *MockRedisMockRecorder) EvalSha(ctx, sha1, keys interface{}, args ...interface{}) *gomock.Call {
+// HDel indicates an expected call of HDel.
+func (mr *MockRedisMockRecorder) HDel(ctx, key interface{}, fields ...interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]interface{}{ctx, sha1, keys}, args...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvalSha", reflect.TypeOf((*MockRedis)(nil).EvalSha), varargs...)
+	varargs := append([]interface{}{ctx, key}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HDel", reflect.TypeOf((*MockRedis)(nil).HDel), varargs...)
+}
+
+// HGet mocks base method.
+func (m *MockRedis) HGet(ctx context.Context, key, field string) *redis.StringCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HGet", ctx, key, field)
+	ret0, _ := ret[0].(*redis.StringCmd)
+	return ret0
+}
+
+// HGet indicates an expected call of HGet.
+func (mr *MockRedisMockRecorder) HGet(ctx, key, field interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGet", reflect.TypeOf((*MockRedis)(nil).HGet), ctx, key, field)
+}
+
+// HSet mocks base method.
+func (m *MockRedis) HSet(ctx context.Context, key string, values ...interface{}) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range values {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "HSet", varargs...)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// HSet indicates an expected call of HSet.
+func (mr *MockRedisMockRecorder) HSet(ctx, key interface{}, values ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, values...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HSet", reflect.TypeOf((*MockRedis)(nil).HSet), varargs...)
+}
+
+// Keys mocks base method.
+func (m *MockRedis) Keys(ctx context.Context, pattern string) *redis.StringSliceCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Keys", ctx, pattern)
+	ret0, _ := ret[0].(*redis.StringSliceCmd)
+	return ret0
+}
+
+// Keys indicates an expected call of Keys.
+func (mr *MockRedisMockRecorder) Keys(ctx, pattern interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Keys", reflect.TypeOf((*MockRedis)(nil).Keys), ctx, pattern)
+}
+
+// Publish mocks base method.
+func (m *MockRedis) Publish(ctx context.Context, channel string, message interface{}) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, channel, message)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockRedisMockRecorder) Publish(ctx, channel, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockRedis)(nil).Publish), ctx, channel, message)
 }
 
 // ScriptLoad mocks base method.
-func (m *MockRedis) ScriptLoad(ctx context.Context, script string) *v8.StringCmd {
+func (m *MockRedis) ScriptLoad(ctx context.Context, script string) *redis.StringCmd {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ScriptLoad", ctx, script)
-	ret0, _ := ret[0].(*v8.StringCmd)
+	ret0, _ := ret[0].(*redis.StringCmd)
 	return ret0
 }
 
@@ -87,15 +163,48 @@ func (mr *MockRedisMockRecorder) ScriptLoad(ctx, script interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScriptLoad", reflect.TypeOf((*MockRedis)(nil).ScriptLoad), ctx, script)
 }
 
+// SetNX mocks base method.
+func (m *MockRedis) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNX", ctx, key, value, expiration)
+	ret0, _ := ret[0].(*redis.BoolCmd)
+	return ret0
+}
+
+// SetNX indicates an expected call of SetNX.
+func (mr *MockRedisMockRecorder) SetNX(ctx, key, value, expiration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNX", reflect.TypeOf((*MockRedis)(nil).SetNX), ctx, key, value, expiration)
+}
+
+// Subscribe mocks base method.
+func (m *MockRedis) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx}
+	for _, a := range channels {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Subscribe", varargs...)
+	ret0, _ := ret[0].(*redis.PubSub)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockRedisMockRecorder) Subscribe(ctx interface{}, channels ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx}, channels...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockRedis)(nil).Subscribe), varargs...)
+}
+
 // ZAdd mocks base method.
-func (m *MockRedis) ZAdd(ctx context.Context, key string, members ...*v8.Z) *v8.IntCmd {
+func (m *MockRedis) ZAdd(ctx context.Context, key string, members ...*redis.Z) *redis.IntCmd {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{ctx, key}
 	for _, a := range members {
 		varargs = append(varargs, a)
 	}
 	ret := m.ctrl.Call(m, "ZAdd", varargs...)
-	ret0, _ := ret[0].(*v8.IntCmd)
+	ret0, _ := ret[0].(*redis.IntCmd)
 	return ret0
 }
 
@@ -105,3 +214,64 @@ func (mr *MockRedisMockRecorder) ZAdd(ctx, key interface{}, members ...interface
 	varargs := append([]interface{}{ctx, key}, members...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZAdd", reflect.TypeOf((*MockRedis)(nil).ZAdd), varargs...)
 }
+
+// ZCard mocks base method.
+func (m *MockRedis) ZCard(ctx context.Context, key string) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZCard", ctx, key)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// ZCard indicates an expected call of ZCard.
+func (mr *MockRedisMockRecorder) ZCard(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZCard", reflect.TypeOf((*MockRedis)(nil).ZCard), ctx, key)
+}
+
+// ZCount mocks base method.
+func (m *MockRedis) ZCount(ctx context.Context, key, min, max string) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZCount", ctx, key, min, max)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// ZCount indicates an expected call of ZCount.
+func (mr *MockRedisMockRecorder) ZCount(ctx, key, min, max interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZCount", reflect.TypeOf((*MockRedis)(nil).ZCount), ctx, key, min, max)
+}
+
+// ZRangeByScore mocks base method.
+func (m *MockRedis) ZRangeByScore(ctx context.Context, key string, opt *redis.ZRangeBy) *redis.StringSliceCmd {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRangeByScore", ctx, key, opt)
+	ret0, _ := ret[0].(*redis.StringSliceCmd)
+	return ret0
+}
+
+// ZRangeByScore indicates an expected call of ZRangeByScore.
+func (mr *MockRedisMockRecorder) ZRangeByScore(ctx, key, opt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRangeByScore", reflect.TypeOf((*MockRedis)(nil).ZRangeByScore), ctx, key, opt)
+}
+
+// ZRem mocks base method.
+func (m *MockRedis) ZRem(ctx context.Context, key string, members ...interface{}) *redis.IntCmd {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ZRem", varargs...)
+	ret0, _ := ret[0].(*redis.IntCmd)
+	return ret0
+}
+
+// ZRem indicates an expected call of ZRem.
+func (mr *MockRedisMockRecorder) ZRem(ctx, key interface{}, members ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRem", reflect.TypeOf((*MockRedis)(nil).ZRem), varargs...)
+}
@@ -0,0 +1,69 @@
+package taskqueue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Handler processes a single consumed task.
+type Handler interface {
+	ServeTask(ctx context.Context, id uuid.UUID, payload []byte) error
+}
+
+// HandlerFunc adapts a plain function to a Handler.
+type HandlerFunc func(ctx context.Context, id uuid.UUID, payload []byte) error
+
+func (f HandlerFunc) ServeTask(ctx context.Context, id uuid.UUID, payload []byte) error {
+	return f(ctx, id, payload)
+}
+
+// MiddlewareFunc wraps a Handler with cross-cutting behavior, such as Recoverer or Timeout.
+type MiddlewareFunc func(Handler) Handler
+
+// ServeMux dispatches a consumed task to the handler registered for its Type, the same way
+// net/http's ServeMux dispatches requests by path. It lets a single TaskQueue multiplex many
+// task types, each processed by its own handler, instead of one ConsumeFunc having to switch on
+// the task's contents itself.
+type ServeMux struct {
+	handlers   map[string]Handler
+	middleware []MiddlewareFunc
+}
+
+// NewServeMux builds an empty ServeMux.
+func NewServeMux() *ServeMux {
+	return &ServeMux{handlers: make(map[string]Handler)}
+}
+
+// Handle registers handler for taskType, replacing any handler previously registered for it.
+func (m *ServeMux) Handle(taskType string, handler Handler) {
+	m.handlers[taskType] = handler
+}
+
+// HandleFunc registers fn for taskType, replacing any handler previously registered for it.
+func (m *ServeMux) HandleFunc(taskType string, fn func(ctx context.Context, id uuid.UUID, payload []byte) error) {
+	m.Handle(taskType, HandlerFunc(fn))
+}
+
+// Use appends middleware to the chain wrapping every handler. Middleware is applied in the order
+// given, so the first one registered is the outermost and runs first.
+func (m *ServeMux) Use(mw ...MiddlewareFunc) {
+	m.middleware = append(m.middleware, mw...)
+}
+
+// Consume implements ConsumeFunc, dispatching a task to the handler registered for its type
+// wrapped with the registered middleware chain. It is meant to be passed directly to
+// TaskQueue.Consume.
+func (m *ServeMux) Consume(ctx context.Context, id uuid.UUID, payload []byte, taskType, _ string) error {
+	handler, ok := m.handlers[taskType]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoHandlerForType, taskType)
+	}
+
+	for i := len(m.middleware) - 1; i >= 0; i-- {
+		handler = m.middleware[i](handler)
+	}
+
+	return handler.ServeTask(ctx, id, payload)
+}
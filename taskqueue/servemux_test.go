@@ -0,0 +1,79 @@
+package taskqueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestServeMux_DispatchesToRegisteredHandler(t *testing.T) {
+	mux := NewServeMux()
+
+	var gotPayload []byte
+
+	mux.HandleFunc("send_email", func(_ context.Context, _ uuid.UUID, payload []byte) error {
+		gotPayload = payload
+
+		return nil
+	})
+
+	if err := mux.Consume(context.Background(), uuid.New(), []byte("hi"), "send_email", "text/plain"); err != nil {
+		t.Fatalf("Consume() error = %v", err)
+	}
+
+	if string(gotPayload) != "hi" {
+		t.Errorf("payload = %q, want %q", gotPayload, "hi")
+	}
+}
+
+func TestServeMux_NoHandlerForType(t *testing.T) {
+	mux := NewServeMux()
+
+	err := mux.Consume(context.Background(), uuid.New(), []byte("hi"), "send_email", "text/plain")
+	if !errors.Is(err, ErrNoHandlerForType) {
+		t.Fatalf("Consume() error = %v, want ErrNoHandlerForType", err)
+	}
+}
+
+func TestServeMux_MiddlewareAppliesOutermostFirst(t *testing.T) {
+	mux := NewServeMux()
+
+	var order []string
+
+	wrap := func(name string) MiddlewareFunc {
+		return func(next Handler) Handler {
+			return HandlerFunc(func(ctx context.Context, id uuid.UUID, payload []byte) error {
+				order = append(order, name)
+
+				return next.ServeTask(ctx, id, payload)
+			})
+		}
+	}
+
+	mux.Use(wrap("outer"), wrap("inner"))
+	mux.HandleFunc("send_email", func(context.Context, uuid.UUID, []byte) error {
+		order = append(order, "handler")
+
+		return nil
+	})
+
+	if err := mux.Consume(context.Background(), uuid.New(), nil, "send_email", ""); err != nil {
+		t.Fatalf("Consume() error = %v", err)
+	}
+
+	want := []string{"outer", "inner", "handler"}
+
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+
+	for i := range want {
+		if order[i] != want[i] {
+			t.Errorf("order = %v, want %v", order, want)
+
+			break
+		}
+	}
+}
@@ -2,7 +2,6 @@ package taskqueue
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -16,31 +15,52 @@ import (
 )
 
 type TaskQueue struct {
-	redis             Redis
-	taskQueueKey      string
-	inProgressTaskKey string
-	consumeScriptSha  string
-	maxRetries        int
-	operationTimeout  time.Duration
+	redis                 Redis
+	codec                 Codec
+	namespace             string
+	queueKey              string
+	taskQueueKey          string
+	taskHashKeyPrefix     string
+	inProgressQueueKey    string
+	deadTaskQueueKey      string
+	completedTaskQueueKey string
+	notifyChannel         string
+	consumeScriptSha      string
+	janitorScriptSha      string
+	recoveryScriptSha     string
+	maxRetries            int
+	operationTimeout      time.Duration
+	deadLetterRetention   time.Duration
+	janitorInterval       time.Duration
+	maxPollInterval       time.Duration
+	visibilityTimeout     time.Duration
+	recoveryInterval      time.Duration
 }
 
+// Task is the unit of work stored in the queue. Payload is opaque to the queue: producers encode
+// it and consumers decode it themselves, using ContentType to agree on the shape out of band.
 type Task struct {
-	ID         uuid.UUID
-	Payload    interface{}
-	RetryCount int
-	Wait       time.Duration
+	ID          uuid.UUID
+	Payload     []byte
+	ContentType string
+	RetryCount  int
+	Wait        time.Duration
+	ProducedAt  time.Time
+
+	// Retention, when greater than zero, keeps the task's data around in the completed-tasks
+	// ZSET for that long after it is successfully consumed, instead of deleting it right away.
+	Retention time.Duration
+
+	// UniqueKey, when set, is the produce-time dedup lock that consume.lua releases once the task
+	// is popped off the queue. Empty for tasks produced without ProduceAtUnique.
+	UniqueKey string
+
+	// Type identifies what kind of work the task represents, so that a ServeMux can dispatch it
+	// to the handler registered for it instead of every task being handled the same way.
+	Type string
 }
 
-type ConsumeFunc func(context.Context, uuid.UUID, interface{}) error
-
-func (t *Task) MarshalBinary() (data []byte, err error) {
-	data, err = json.Marshal(t)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal *Task: %w", err)
-	}
-
-	return data, nil
-}
+type ConsumeFunc func(ctx context.Context, id uuid.UUID, payload []byte, taskType, contentType string) error
 
 func NewTaskQueue(ctx context.Context, redisClient Redis, options *Options) (*TaskQueue, error) {
 	options.setDefaults()
@@ -55,18 +75,56 @@ func NewTaskQueue(ctx context.Context, redisClient Redis, options *Options) (*Ta
 		return nil, fmt.Errorf("failed to load consume script file into redis: %w", err)
 	}
 
+	recoveryScriptBytes, err := os.ReadFile(getRecoveryScriptPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recovery script file: %w", err)
+	}
+
+	recoveryScriptSHA, err := redisClient.ScriptLoad(ctx, string(recoveryScriptBytes)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recovery script file into redis: %w", err)
+	}
+
 	taskQueue := &TaskQueue{
-		redis:        redisClient,
-		taskQueueKey: fmt.Sprintf("taskqueue:%s:tasks:%s", options.Namespace, options.QueueKey),
-		inProgressTaskKey: fmt.Sprintf(
-			"taskqueue:%s:workers:%s:tasks:%s",
-			options.Namespace, options.WorkerID, options.QueueKey,
-		),
-		consumeScriptSha: consumeScriptSHA,
-		maxRetries:       options.MaxRetries,
-		operationTimeout: options.OperationTimeout,
+		redis:                 redisClient,
+		codec:                 options.Codec,
+		namespace:             options.Namespace,
+		queueKey:              options.QueueKey,
+		taskQueueKey:          taskQueueKey(options.Namespace, options.QueueKey),
+		taskHashKeyPrefix:     taskHashKeyPrefix(options.Namespace),
+		inProgressQueueKey:    inProgressQueueKey(options.Namespace, options.QueueKey),
+		deadTaskQueueKey:      deadTaskQueueKey(options.Namespace, options.QueueKey),
+		completedTaskQueueKey: completedTaskQueueKey(options.Namespace, options.QueueKey),
+		notifyChannel:         notifyChannelKey(options.Namespace, options.QueueKey),
+		consumeScriptSha:      consumeScriptSHA,
+		recoveryScriptSha:     recoveryScriptSHA,
+		maxRetries:            options.MaxRetries,
+		operationTimeout:      options.OperationTimeout,
+		deadLetterRetention:   options.DeadLetterRetention,
+		janitorInterval:       options.JanitorInterval,
+		maxPollInterval:       options.MaxPollInterval,
+		visibilityTimeout:     options.VisibilityTimeout,
+		recoveryInterval:      options.RecoveryInterval,
 	}
 
+	if options.EnableJanitor {
+		janitorScriptBytes, err := os.ReadFile(getJanitorScriptPath())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read janitor script file: %w", err)
+		}
+
+		janitorScriptSHA, err := redisClient.ScriptLoad(ctx, string(janitorScriptBytes)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load janitor script file into redis: %w", err)
+		}
+
+		taskQueue.janitorScriptSha = janitorScriptSHA
+
+		go taskQueue.runJanitor(ctx)
+	}
+
+	go taskQueue.runRecovery(ctx)
+
 	return taskQueue, nil
 }
 
@@ -74,17 +132,41 @@ func NewDefaultRedis(options *Options) *redis.Client {
 	return redis.NewClient(&redis.Options{Addr: options.StorageAddress}) //nolint:exhaustruct
 }
 
-func (t *TaskQueue) ProduceAt(ctx context.Context, payload interface{}, executeAt time.Time) (uuid.UUID, error) {
+func (t *TaskQueue) ProduceAt(
+	ctx context.Context,
+	taskType string,
+	payload []byte,
+	contentType string,
+	executeAt time.Time,
+) (uuid.UUID, error) {
+	return t.ProduceAtWithOptions(ctx, taskType, payload, contentType, executeAt, 0)
+}
+
+// ProduceAtWithOptions is like ProduceAt, but additionally accepts a retention duration: if
+// greater than zero, the task's data is kept in the completed-tasks ZSET for that long after it
+// is successfully consumed, instead of being deleted right away.
+func (t *TaskQueue) ProduceAtWithOptions(
+	ctx context.Context,
+	taskType string,
+	payload []byte,
+	contentType string,
+	executeAt time.Time,
+	retention time.Duration,
+) (uuid.UUID, error) {
 	logger := newLogger()
 
 	task := &Task{
-		ID:         uuid.New(),
-		Payload:    payload,
-		RetryCount: 0,
-		Wait:       0,
+		ID:          uuid.New(),
+		Payload:     payload,
+		ContentType: contentType,
+		RetryCount:  0,
+		Wait:        0,
+		ProducedAt:  time.Now(),
+		Retention:   retention,
+		Type:        taskType,
 	}
 
-	logger.Debugf("producing task %s %v", task.ID, task.Payload)
+	logger.Debugf("producing task %s", task.ID)
 
 	err := t.produceAt(ctx, task, executeAt)
 	if err != nil {
@@ -94,27 +176,110 @@ func (t *TaskQueue) ProduceAt(ctx context.Context, payload interface{}, executeA
 	return task.ID, nil
 }
 
+// ProduceAtUnique is like ProduceAt, but coalesces bursts of identical payloads: if another task
+// with the same payload was produced less than ttl ago and has not yet been popped off the
+// queue, it returns ErrDuplicateTask instead of enqueuing a duplicate.
+func (t *TaskQueue) ProduceAtUnique(
+	ctx context.Context,
+	taskType string,
+	payload []byte,
+	contentType string,
+	executeAt time.Time,
+	ttl time.Duration,
+) (uuid.UUID, error) {
+	logger := newLogger()
+
+	lockKey := uniqueTaskKey(t.namespace, t.queueKey, payload)
+
+	acquired, err := t.redis.SetNX(ctx, lockKey, "1", ttl).Result()
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to acquire unique task lock: %w", err)
+	}
+
+	if !acquired {
+		return uuid.Nil, ErrDuplicateTask
+	}
+
+	task := &Task{
+		ID:          uuid.New(),
+		Payload:     payload,
+		ContentType: contentType,
+		RetryCount:  0,
+		Wait:        0,
+		ProducedAt:  time.Now(),
+		UniqueKey:   lockKey,
+		Type:        taskType,
+	}
+
+	logger.Debugf("producing unique task %s", task.ID)
+
+	if err := t.produceAt(ctx, task, executeAt); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to produce unique task: %w", err)
+	}
+
+	return task.ID, nil
+}
+
+// Extend sets the visibility deadline of an in-progress task to duration from now, giving a
+// consumer more time to finish processing it before the recovery goroutine considers it stuck
+// (e.g. because its worker crashed) and puts it back on the queue for another worker to pick up.
+// Callers processing a task longer than Options.VisibilityTimeout must call this periodically.
+func (t *TaskQueue) Extend(ctx context.Context, id uuid.UUID, duration time.Duration) error {
+	if err := t.redis.ZAdd(ctx, t.inProgressQueueKey, &redis.Z{
+		Score:  float64(time.Now().Add(duration).Unix()),
+		Member: id.String(),
+	}).Err(); err != nil {
+		return fmt.Errorf("failed to extend task %s visibility: %w", id, err)
+	}
+
+	return nil
+}
+
 func (t *TaskQueue) Consume(
 	ctx context.Context,
 	consume ConsumeFunc,
 ) {
-	var (
-		ticker = time.NewTicker(time.Second)
-		logger = newLogger().WithFields(logrus.Fields{
-			"operation": "consumer",
-		})
-	)
+	logger := newLogger().WithFields(logrus.Fields{
+		"operation": "consumer",
+	})
+
+	pubsub := t.redis.Subscribe(ctx, t.notifyChannel)
+	defer pubsub.Close()
+
+	notify := pubsub.Channel()
 
 	for {
+		if ctx.Err() != nil {
+			logger.Info("stopping")
+
+			return
+		}
+
+		logger.Debug("consuming task")
+
+		wait, err := t.consume(ctx, consume)
+		if err != nil {
+			logger.WithError(err).Error("failed to call consume function")
+		}
+
+		if wait <= 0 {
+			continue
+		}
+
+		if wait > t.maxPollInterval {
+			wait = t.maxPollInterval
+		}
+
+		timer := time.NewTimer(wait)
+
 		select {
-		case <-ticker.C:
-			logger.Info("consuming task")
+		case <-notify:
+			timer.Stop()
 
-			if err := t.consume(ctx, consume); err != nil {
-				logger.WithError(err).Error("failed to call consume function")
-			}
+		case <-timer.C:
 
 		case <-ctx.Done():
+			timer.Stop()
 			logger.Info("stopping")
 
 			return
@@ -129,82 +294,116 @@ func getConsumeScriptPath() string {
 	return filepath.Join(basepath, "consume.lua")
 }
 
+// consume runs one iteration of the consume loop and returns how long to wait before trying
+// again: zero if a task was just handled (there may be more ready immediately), or the delay
+// until the next scheduled task if the queue is currently empty.
 func (t *TaskQueue) consume(
 	ctx context.Context,
 	consume ConsumeFunc,
-) error {
+) (time.Duration, error) {
 	logger := newLogger()
 
-	task, err := t.getTask(ctx)
+	task, wait, err := t.getTask(ctx)
 	if errors.Is(err, ErrNoTaskToConsume) {
-		return nil
+		return wait, nil
 	} else if err != nil {
-		return fmt.Errorf("failed to get task: %w", err)
+		return t.maxPollInterval, fmt.Errorf("failed to get task: %w", err)
 	}
 
 	logger = withTaskLabels(logger, task)
-	defer t.removeInProgressTask(ctx, task)
+	defer t.completeInProgressTask(ctx, task)
 
 	logger.Debug("consuming task")
 
-	err = consume(ctx, task.ID, task.Payload)
+	err = consume(ctx, task.ID, task.Payload, task.Type, task.ContentType)
 	if err != nil {
 		logger.WithError(err).Debug("failed to consume, retrying after backoff")
 
 		retryErr := t.produceRetry(ctx, task)
 		if retryErr != nil {
-			return ErrTaskLost(
+			return 0, ErrTaskLost(
 				task.ID,
 				fmt.Errorf("failed to run consume func and failed to enqueue it for retry: %w", retryErr),
 			)
 		}
 
-		return fmt.Errorf("failed to run consume func: %w", err)
+		return 0, fmt.Errorf("failed to run consume func: %w", err)
 	}
 
 	logger.Debug("successfully consumed task")
 
-	return nil
+	if task.Retention > 0 {
+		if err := t.completeTask(ctx, task); err != nil {
+			logger.WithError(err).Warn("failed to retain completed task")
+		}
+	} else if err := t.redis.HDel(ctx, taskHashKey(t.namespace, task.ID), "data", "unique_key").Err(); err != nil {
+		logger.WithError(err).Warn("failed to delete task data after consuming it")
+	}
+
+	return 0, nil
 }
 
-func (t *TaskQueue) getTask(ctx context.Context) (*Task, error) {
+// getTask pops the next ready task off the queue. If none is ready, it returns ErrNoTaskToConsume
+// along with how long to wait before the next scheduled task becomes ready, or maxPollInterval
+// when no task is scheduled at all. The delay is not capped here; Consume caps it at
+// maxPollInterval.
+func (t *TaskQueue) getTask(ctx context.Context) (*Task, time.Duration, error) {
 	logger := newLogger()
 
 	now := time.Now()
 	keys := []string{
 		t.taskQueueKey,
-		t.inProgressTaskKey,
+		t.inProgressQueueKey,
 	}
 	args := []interface{}{
 		fmt.Sprintf("%d", now.Unix()),
+		t.taskHashKeyPrefix,
+		fmt.Sprintf("%d", int64(t.visibilityTimeout.Seconds())),
 	}
 
 	logger.Debugf("fetching task to execute")
 
 	taskInterface, err := t.redis.EvalSha(ctx, t.consumeScriptSha, keys, args...).Result()
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute consume script: %w", err)
+		return nil, t.maxPollInterval, fmt.Errorf("failed to execute consume script: %w", err)
 	}
 
-	if taskInterface == StatusOK {
-		logger.Debug("no tasks to execute")
-
-		return nil, ErrNoTaskToConsume
+	if status, ok := taskInterface.([]interface{}); ok {
+		return nil, t.parseWait(status, logger), ErrNoTaskToConsume
 	}
 
 	taskStr, ok := taskInterface.(string)
 	if !ok {
-		return nil, fmt.Errorf("failed to cast task: %w", ErrInvalidTaskType)
+		return nil, t.maxPollInterval, fmt.Errorf("failed to cast task: %w", ErrInvalidTaskType)
 	}
 
-	task := new(Task)
-
-	err = json.Unmarshal([]byte(taskStr), task)
+	task, err := t.codec.Decode([]byte(taskStr))
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal task and permanently lost it: %w", err)
+		return nil, 0, fmt.Errorf("failed to decode task and permanently lost it: %w", err)
+	}
+
+	return task, 0, nil
+}
+
+// parseWait extracts the delay from consume.lua's {"OK", delaySeconds} reply: delaySeconds is -1
+// when the queue has no tasks at all, otherwise the number of seconds until the next one is due.
+func (t *TaskQueue) parseWait(status []interface{}, logger *logrus.Entry) time.Duration {
+	if len(status) != 2 || status[0] != StatusOK { //nolint:gomnd
+		logger.Warnf("unexpected consume script reply: %v", status)
+
+		return t.maxPollInterval
+	}
+
+	delaySeconds, ok := status[1].(int64)
+	if !ok || delaySeconds < 0 {
+		logger.Debug("no tasks scheduled")
+
+		return t.maxPollInterval
 	}
 
-	return task, nil
+	logger.Debugf("next task scheduled in %ds", delaySeconds)
+
+	return time.Duration(delaySeconds) * time.Second
 }
 
 func (t *TaskQueue) produceAt(
@@ -212,11 +411,42 @@ func (t *TaskQueue) produceAt(
 	task *Task,
 	executeAt time.Time,
 ) error {
+	encoded, err := t.codec.Encode(task)
+	if err != nil {
+		return fmt.Errorf("failed to encode task: %w", err)
+	}
+
+	hashKey := taskHashKey(t.namespace, task.ID)
+
+	if task.UniqueKey != "" {
+		if err := t.redis.HSet(ctx, hashKey, "data", encoded, "unique_key", task.UniqueKey).Err(); err != nil {
+			return fmt.Errorf("failed to store task: %w", err)
+		}
+	} else {
+		if err := t.redis.HSet(ctx, hashKey, "data", encoded).Err(); err != nil {
+			return fmt.Errorf("failed to store task: %w", err)
+		}
+
+		// Clear out any unique_key left over from an earlier production of this same task ID (e.g.
+		// a retry or crash recovery): HSet above only ever sets "data", so a stale unique_key would
+		// otherwise outlive the production that set it, and a later pop would DEL a lock belonging
+		// to an unrelated task produced afterwards with the same deterministic key.
+		if err := t.redis.HDel(ctx, hashKey, "unique_key").Err(); err != nil {
+			return fmt.Errorf("failed to clear stale unique key: %w", err)
+		}
+	}
+
 	if err := t.redis.ZAdd(ctx, t.taskQueueKey, &redis.Z{
 		Score:  float64(executeAt.Unix()),
-		Member: task,
+		Member: task.ID.String(),
 	}).Err(); err != nil {
-		return fmt.Errorf("failed to zadd message: %w", err)
+		return fmt.Errorf("failed to zadd task id: %w", err)
+	}
+
+	if !executeAt.After(time.Now()) {
+		if err := t.redis.Publish(ctx, t.notifyChannel, task.ID.String()).Err(); err != nil {
+			newLogger().WithError(err).Warn("failed to publish task wakeup notification")
+		}
 	}
 
 	return nil
@@ -226,7 +456,11 @@ func (t *TaskQueue) produceRetry(ctx context.Context, task *Task) error {
 	now := time.Now()
 
 	if t.maxRetries >= 0 && task.RetryCount >= t.maxRetries {
-		return fmt.Errorf("%w: %s, %d", ErrMaxTaskReties, task.ID, task.RetryCount)
+		if err := t.deadLetterTask(ctx, task); err != nil {
+			return fmt.Errorf("%w: %s, %d: %w", ErrMaxTaskReties, task.ID, task.RetryCount, err)
+		}
+
+		return nil
 	}
 
 	wait := time.Second
@@ -235,10 +469,14 @@ func (t *TaskQueue) produceRetry(ctx context.Context, task *Task) error {
 	}
 
 	retryTask := &Task{
-		ID:         task.ID,
-		Payload:    task.Payload,
-		RetryCount: task.RetryCount + 1,
-		Wait:       wait,
+		ID:          task.ID,
+		Payload:     task.Payload,
+		ContentType: task.ContentType,
+		RetryCount:  task.RetryCount + 1,
+		Wait:        wait,
+		ProducedAt:  task.ProducedAt,
+		Retention:   task.Retention,
+		Type:        task.Type,
 	}
 
 	executeAt := now.Add(wait)
@@ -251,14 +489,232 @@ func (t *TaskQueue) produceRetry(ctx context.Context, task *Task) error {
 	return nil
 }
 
-func (t *TaskQueue) removeInProgressTask(ctx context.Context, task *Task) {
+func (t *TaskQueue) completeTask(ctx context.Context, task *Task) error {
+	if err := t.redis.ZAdd(ctx, t.completedTaskQueueKey, &redis.Z{
+		Score:  float64(time.Now().Add(task.Retention).Unix()),
+		Member: task.ID.String(),
+	}).Err(); err != nil {
+		return fmt.Errorf("failed to retain completed task: %w", err)
+	}
+
+	return nil
+}
+
+func (t *TaskQueue) deadLetterTask(ctx context.Context, task *Task) error {
+	if err := t.redis.ZAdd(ctx, t.deadTaskQueueKey, &redis.Z{
+		Score:  float64(time.Now().Add(t.deadLetterRetention).Unix()),
+		Member: task.ID.String(),
+	}).Err(); err != nil {
+		return fmt.Errorf("failed to move task to dead-letter queue: %w", err)
+	}
+
+	return nil
+}
+
+// Requeue resurrects a dead task, resetting its retry count and enqueueing it to run immediately.
+func (t *TaskQueue) Requeue(ctx context.Context, id uuid.UUID) error {
+	data, err := t.redis.HGet(ctx, taskHashKey(t.namespace, id), "data").Result()
+	if err != nil {
+		return fmt.Errorf("failed to read dead task %s: %w", id, err)
+	}
+
+	task, err := t.codec.Decode([]byte(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode dead task %s: %w", id, err)
+	}
+
+	task.RetryCount = 0
+	task.Wait = 0
+
+	if err := t.redis.ZRem(ctx, t.deadTaskQueueKey, id.String()).Err(); err != nil {
+		return fmt.Errorf("failed to remove task %s from dead-letter queue: %w", id, err)
+	}
+
+	if err := t.produceAt(ctx, task, time.Now()); err != nil {
+		return fmt.Errorf("failed to requeue task %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// ListDead returns every task sitting in the dead-letter queue, in one slice. It takes no page
+// or size arguments and does not page through them.
+func (t *TaskQueue) ListDead(ctx context.Context) ([]*Task, error) {
+	ids, err := t.redis.ZRangeByScore(ctx, t.deadTaskQueueKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead task ids: %w", err)
+	}
+
+	tasks := make([]*Task, 0, len(ids))
+
+	for _, id := range ids {
+		data, err := t.redis.HGet(ctx, taskHashKeyPrefix(t.namespace)+id, "data").Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dead task %s: %w", id, err)
+		}
+
+		task, err := t.codec.Decode([]byte(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode dead task %s: %w", id, err)
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+func getJanitorScriptPath() string {
+	_, b, _, _ := runtime.Caller(0)
+	basepath := filepath.Dir(b)
+
+	return filepath.Join(basepath, "janitor.lua")
+}
+
+func (t *TaskQueue) runJanitor(ctx context.Context) {
+	var (
+		ticker = time.NewTicker(t.janitorInterval)
+		logger = newLogger().WithFields(logrus.Fields{
+			"operation": "janitor",
+		})
+	)
+
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			t.cleanupExpired(ctx, t.deadTaskQueueKey, logger)
+			t.cleanupExpired(ctx, t.completedTaskQueueKey, logger)
+
+		case <-ctx.Done():
+			logger.Info("stopping")
+
+			return
+		}
+	}
+}
+
+func (t *TaskQueue) cleanupExpired(ctx context.Context, key string, logger *logrus.Entry) {
+	keys := []string{key}
+	args := []interface{}{
+		fmt.Sprintf("%d", time.Now().Unix()),
+		t.taskHashKeyPrefix,
+	}
+
+	if err := t.redis.EvalSha(ctx, t.janitorScriptSha, keys, args...).Err(); err != nil {
+		logger.WithError(err).WithField("key", key).Error("failed to purge expired tasks")
+	}
+}
+
+func (t *TaskQueue) completeInProgressTask(ctx context.Context, task *Task) {
 	logger := newLogger()
 	logger = withTaskLabels(logger, task)
 
-	err := t.redis.Del(ctx, t.inProgressTaskKey).Err()
+	err := t.redis.ZRem(ctx, t.inProgressQueueKey, task.ID.String()).Err()
 	if err != nil {
 		logger.
 			WithError(err).
-			Warn("failed to delete worker in progress task, might duplicate if worker restart now")
+			Warn("failed to remove in progress task, recovery might duplicate it once its visibility times out")
+	}
+}
+
+func getRecoveryScriptPath() string {
+	_, b, _, _ := runtime.Caller(0)
+	basepath := filepath.Dir(b)
+
+	return filepath.Join(basepath, "recovery.lua")
+}
+
+// runRecovery periodically retries any in-progress task whose visibility deadline has passed
+// without being extended or completed, which happens when the worker that claimed it crashed or
+// was killed before finishing it.
+func (t *TaskQueue) runRecovery(ctx context.Context) {
+	var (
+		ticker = time.NewTicker(t.recoveryInterval)
+		logger = newLogger().WithFields(logrus.Fields{
+			"operation": "recovery",
+		})
+	)
+
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			t.recoverStuckTasks(ctx, logger)
+
+		case <-ctx.Done():
+			logger.Info("stopping")
+
+			return
+		}
+	}
+}
+
+func (t *TaskQueue) recoverStuckTasks(ctx context.Context, logger *logrus.Entry) {
+	keys := []string{t.inProgressQueueKey}
+	args := []interface{}{fmt.Sprintf("%d", time.Now().Unix())}
+
+	reply, err := t.redis.EvalSha(ctx, t.recoveryScriptSha, keys, args...).Result()
+	if err != nil {
+		logger.WithError(err).Error("failed to recover stuck tasks")
+
+		return
+	}
+
+	ids, ok := reply.([]interface{})
+	if !ok {
+		logger.Warnf("unexpected recovery script reply: %v", reply)
+
+		return
+	}
+
+	for _, idInterface := range ids {
+		id, ok := idInterface.(string)
+		if !ok {
+			continue
+		}
+
+		if err := t.recoverTask(ctx, id); err != nil {
+			logger.WithError(err).Warnf("failed to recover stuck task %s", id)
+
+			continue
+		}
+
+		logger.Warnf("recovered stuck task %s after its visibility timed out", id)
+	}
+
+	if len(ids) > 0 {
+		if err := t.redis.Publish(ctx, t.notifyChannel, "recovered").Err(); err != nil {
+			logger.WithError(err).Warn("failed to publish recovery wakeup notification")
+		}
+	}
+}
+
+// recoverTask re-produces a task whose worker crashed before finishing it, routing it through the
+// same retry/dead-letter decision produceRetry makes for a task whose consume function returned
+// an error, so a task that reliably crashes its worker (e.g. an OOM kill) eventually lands in the
+// dead-letter queue instead of being recovered forever.
+func (t *TaskQueue) recoverTask(ctx context.Context, id string) error {
+	hashKey := taskHashKeyPrefix(t.namespace) + id
+
+	data, err := t.redis.HGet(ctx, hashKey, "data").Result()
+	if err != nil {
+		return fmt.Errorf("failed to read recovered task: %w", err)
 	}
+
+	task, err := t.codec.Decode([]byte(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode recovered task: %w", err)
+	}
+
+	if err := t.produceRetry(ctx, task); err != nil {
+		return fmt.Errorf("failed to retry recovered task: %w", err)
+	}
+
+	return nil
 }
@@ -0,0 +1,285 @@
+package taskqueue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+)
+
+func newTestQueue(redisClient Redis) *TaskQueue {
+	const namespace, queueKey = "ns", "q"
+
+	return &TaskQueue{
+		redis:                 redisClient,
+		codec:                 JSONCodec{},
+		namespace:             namespace,
+		queueKey:              queueKey,
+		taskQueueKey:          taskQueueKey(namespace, queueKey),
+		taskHashKeyPrefix:     taskHashKeyPrefix(namespace),
+		inProgressQueueKey:    inProgressQueueKey(namespace, queueKey),
+		deadTaskQueueKey:      deadTaskQueueKey(namespace, queueKey),
+		completedTaskQueueKey: completedTaskQueueKey(namespace, queueKey),
+		notifyChannel:         notifyChannelKey(namespace, queueKey),
+		maxRetries:            3,
+		maxPollInterval:       30 * time.Second,
+	}
+}
+
+func intCmd(ctx context.Context) *redis.IntCmd {
+	cmd := redis.NewIntCmd(ctx)
+	cmd.SetVal(1)
+
+	return cmd
+}
+
+func stringCmd(ctx context.Context, val string) *redis.StringCmd {
+	cmd := redis.NewStringCmd(ctx)
+	cmd.SetVal(val)
+
+	return cmd
+}
+
+// TestProduceAt_ClearsStaleUniqueKey covers the bug where re-producing a task without a
+// UniqueKey (as produceRetry and crash recovery do) left a previous production's unique_key hash
+// field in place, causing a later pop to release a lock that belongs to an unrelated task.
+func TestProduceAt_ClearsStaleUniqueKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRedis := NewMockRedis(ctrl)
+	queue := newTestQueue(mockRedis)
+
+	ctx := context.Background()
+	task := &Task{ID: uuid.New(), Payload: []byte("p")}
+	hashKey := taskHashKey(queue.namespace, task.ID)
+
+	mockRedis.EXPECT().HSet(ctx, hashKey, "data", gomock.Any()).Return(intCmd(ctx))
+	mockRedis.EXPECT().HDel(ctx, hashKey, "unique_key").Return(intCmd(ctx))
+	mockRedis.EXPECT().ZAdd(ctx, queue.taskQueueKey, gomock.Any()).Return(intCmd(ctx))
+
+	if err := queue.produceAt(ctx, task, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("produceAt() error = %v", err)
+	}
+}
+
+// TestProduceAt_KeepsUniqueKey ensures a task produced with a UniqueKey still stores it, so the
+// fix above doesn't regress the original dedup-lock-release path in consume.lua.
+func TestProduceAt_KeepsUniqueKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRedis := NewMockRedis(ctrl)
+	queue := newTestQueue(mockRedis)
+
+	ctx := context.Background()
+	task := &Task{ID: uuid.New(), Payload: []byte("p"), UniqueKey: "taskqueue:ns:unique:q:abc"}
+	hashKey := taskHashKey(queue.namespace, task.ID)
+
+	mockRedis.EXPECT().HSet(ctx, hashKey, "data", gomock.Any(), "unique_key", task.UniqueKey).Return(intCmd(ctx))
+	mockRedis.EXPECT().ZAdd(ctx, queue.taskQueueKey, gomock.Any()).Return(intCmd(ctx))
+
+	if err := queue.produceAt(ctx, task, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("produceAt() error = %v", err)
+	}
+}
+
+// TestProduceRetry_DeadLettersOnceMaxRetriesReached covers both produceRetry's own retry-exhausted
+// path and, by extension, recoverTask's reuse of it for crash-recovered tasks.
+func TestProduceRetry_DeadLettersOnceMaxRetriesReached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRedis := NewMockRedis(ctrl)
+	queue := newTestQueue(mockRedis)
+
+	ctx := context.Background()
+	task := &Task{ID: uuid.New(), Payload: []byte("p"), RetryCount: queue.maxRetries}
+
+	mockRedis.EXPECT().ZAdd(ctx, queue.deadTaskQueueKey, gomock.Any()).Return(intCmd(ctx))
+
+	if err := queue.produceRetry(ctx, task); err != nil {
+		t.Fatalf("produceRetry() error = %v", err)
+	}
+}
+
+func TestProduceRetry_RequeuesBelowMaxRetries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRedis := NewMockRedis(ctrl)
+	queue := newTestQueue(mockRedis)
+
+	ctx := context.Background()
+	task := &Task{ID: uuid.New(), Payload: []byte("p"), RetryCount: 0}
+	hashKey := taskHashKey(queue.namespace, task.ID)
+
+	mockRedis.EXPECT().HSet(ctx, hashKey, "data", gomock.Any()).Return(intCmd(ctx))
+	mockRedis.EXPECT().HDel(ctx, hashKey, "unique_key").Return(intCmd(ctx))
+	mockRedis.EXPECT().ZAdd(ctx, queue.taskQueueKey, gomock.Any()).Return(intCmd(ctx))
+
+	if err := queue.produceRetry(ctx, task); err != nil {
+		t.Fatalf("produceRetry() error = %v", err)
+	}
+}
+
+func TestParseWait(t *testing.T) {
+	queue := newTestQueue(nil)
+	logger := newLogger()
+
+	tests := []struct {
+		name   string
+		status []interface{}
+		want   time.Duration
+	}{
+		{
+			name:   "no tasks scheduled",
+			status: []interface{}{StatusOK, int64(-1)},
+			want:   queue.maxPollInterval,
+		},
+		{
+			name:   "next task due in 5 seconds",
+			status: []interface{}{StatusOK, int64(5)},
+			want:   5 * time.Second,
+		},
+		{
+			name:   "unexpected reply shape",
+			status: []interface{}{StatusOK},
+			want:   queue.maxPollInterval,
+		},
+		{
+			name:   "unexpected status",
+			status: []interface{}{"ERR", int64(5)},
+			want:   queue.maxPollInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := queue.parseWait(tt.status, logger); got != tt.want {
+				t.Errorf("parseWait() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompleteTask_RetainsInCompletedQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRedis := NewMockRedis(ctrl)
+	queue := newTestQueue(mockRedis)
+
+	ctx := context.Background()
+	task := &Task{ID: uuid.New(), Payload: []byte("p"), Retention: time.Hour}
+
+	mockRedis.EXPECT().ZAdd(ctx, queue.completedTaskQueueKey, gomock.Any()).Return(intCmd(ctx))
+
+	if err := queue.completeTask(ctx, task); err != nil {
+		t.Fatalf("completeTask() error = %v", err)
+	}
+}
+
+func TestDeadLetterTask_MovesToDeadQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRedis := NewMockRedis(ctrl)
+	queue := newTestQueue(mockRedis)
+
+	ctx := context.Background()
+	task := &Task{ID: uuid.New(), Payload: []byte("p")}
+
+	mockRedis.EXPECT().ZAdd(ctx, queue.deadTaskQueueKey, gomock.Any()).Return(intCmd(ctx))
+
+	if err := queue.deadLetterTask(ctx, task); err != nil {
+		t.Fatalf("deadLetterTask() error = %v", err)
+	}
+}
+
+// TestRequeue_ResetsRetryCountAndRemovesFromDeadQueue covers resurrecting a task that exhausted
+// its retries: Requeue must reset the bookkeeping a fresh produce would have, not just re-enqueue
+// the stale retry count, and must unconditionally drop it from the dead-letter ZSET.
+func TestRequeue_ResetsRetryCountAndRemovesFromDeadQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRedis := NewMockRedis(ctrl)
+	queue := newTestQueue(mockRedis)
+
+	ctx := context.Background()
+	id := uuid.New()
+	hashKey := taskHashKey(queue.namespace, id)
+
+	dead := &Task{ID: id, Payload: []byte("p"), RetryCount: 3, Wait: 4 * time.Second}
+
+	encoded, err := queue.codec.Encode(dead)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	mockRedis.EXPECT().HGet(ctx, hashKey, "data").Return(stringCmd(ctx, string(encoded)))
+	mockRedis.EXPECT().ZRem(ctx, queue.deadTaskQueueKey, id.String()).Return(intCmd(ctx))
+	mockRedis.EXPECT().
+		HSet(ctx, hashKey, "data", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, values ...interface{}) *redis.IntCmd {
+			reproduced, err := queue.codec.Decode(values[1].([]byte))
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			if reproduced.RetryCount != 0 {
+				t.Errorf("reproduced RetryCount = %d, want 0", reproduced.RetryCount)
+			}
+
+			if reproduced.Wait != 0 {
+				t.Errorf("reproduced Wait = %s, want 0", reproduced.Wait)
+			}
+
+			return intCmd(ctx)
+		})
+	mockRedis.EXPECT().HDel(ctx, hashKey, "unique_key").Return(intCmd(ctx))
+	mockRedis.EXPECT().ZAdd(ctx, queue.taskQueueKey, gomock.Any()).Return(intCmd(ctx))
+	mockRedis.EXPECT().Publish(ctx, queue.notifyChannel, id.String()).Return(intCmd(ctx))
+
+	if err := queue.Requeue(ctx, id); err != nil {
+		t.Fatalf("Requeue() error = %v", err)
+	}
+}
+
+func TestListDead_DecodesEachTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRedis := NewMockRedis(ctrl)
+	queue := newTestQueue(mockRedis)
+
+	ctx := context.Background()
+	task := &Task{ID: uuid.New(), Payload: []byte("p")}
+
+	encoded, err := queue.codec.Encode(task)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	mockRedis.EXPECT().
+		ZRangeByScore(ctx, queue.deadTaskQueueKey, &redis.ZRangeBy{Min: "-inf", Max: "+inf"}).
+		Return(stringSliceCmd(ctx, task.ID.String()))
+	mockRedis.EXPECT().
+		HGet(ctx, taskHashKeyPrefix(queue.namespace)+task.ID.String(), "data").
+		Return(stringCmd(ctx, string(encoded)))
+
+	tasks, err := queue.ListDead(ctx)
+	if err != nil {
+		t.Fatalf("ListDead() error = %v", err)
+	}
+
+	if len(tasks) != 1 || tasks[0].ID != task.ID {
+		t.Fatalf("ListDead() = %+v, want a single task with ID %s", tasks, task.ID)
+	}
+}
+
+// TestCleanupExpired_PurgesBothTheZSetAndItsTaskHashes covers the janitor's two-step cleanup of a
+// retention ZSET: evaluating the janitor script must both HDEL the expired tasks' data and
+// ZREMRANGEBYSCORE them out of the ZSET, which janitor.lua does atomically server-side.
+func TestCleanupExpired_PurgesBothTheZSetAndItsTaskHashes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRedis := NewMockRedis(ctrl)
+	queue := newTestQueue(mockRedis)
+	queue.janitorScriptSha = "deadbeef"
+
+	ctx := context.Background()
+
+	mockRedis.EXPECT().
+		EvalSha(ctx, queue.janitorScriptSha, []string{queue.deadTaskQueueKey}, gomock.Any(), queue.taskHashKeyPrefix).
+		Return(redis.NewCmd(ctx))
+
+	queue.cleanupExpired(ctx, queue.deadTaskQueueKey, newLogger())
+}